@@ -2,6 +2,7 @@ package core
 
 import (
 	"math"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -19,8 +20,19 @@ type Filter struct {
 	latest   int64
 	skip     int
 	address  []common.Address
+	excluded map[common.Address]struct{}
 	max      int
 	topics   [][]common.Hash
+	reverse  bool
+
+	addressBits []*big.Int   // Precomputed bloom bits of the filtered addresses
+	topicBits   [][]*big.Int // Precomputed bloom bits of the filtered topics (nil = wildcard)
+
+	minTopics   int  // Minimum number of topics a log must have
+	exactTopics int  // Exact number of topics a log must have
+	hasExact    bool // Whether exactTopics is enforced at all
+
+	scanHook func(*types.Block) // Method to call upon visiting a block (testing only)
 
 	BlockCallback   func(*types.Block, state.Logs)
 	PendingCallback func(*types.Transaction)
@@ -46,10 +58,48 @@ func (self *Filter) SetLatestBlock(latest int64) {
 
 func (self *Filter) SetAddress(addr []common.Address) {
 	self.address = addr
+
+	self.addressBits = make([]*big.Int, len(addr))
+	for i, address := range addr {
+		self.addressBits[i] = types.Bloom9(address.Bytes())
+	}
+}
+
+// SetExcludedAddresses sets a list of addresses whose logs are dropped from the
+// results. The exclusion is only applied to the logs of blocks that already
+// passed the bloom filter, so it does not narrow down which blocks get scanned.
+func (self *Filter) SetExcludedAddresses(addr []common.Address) {
+	self.excluded = make(map[common.Address]struct{}, len(addr))
+	for _, address := range addr {
+		self.excluded[address] = struct{}{}
+	}
 }
 
 func (self *Filter) SetTopics(topics [][]common.Hash) {
 	self.topics = topics
+
+	self.topicBits = make([][]*big.Int, len(topics))
+	for i, sub := range topics {
+		self.topicBits[i] = make([]*big.Int, len(sub))
+		for j, topic := range sub {
+			if (topic != common.Hash{}) {
+				self.topicBits[i][j] = types.Bloom9(topic.Bytes())
+			}
+		}
+	}
+}
+
+// SetMinTopics sets the minimum number of topics a log needs to have in order
+// to be matched, regardless of how many topic positions are being filtered on.
+func (self *Filter) SetMinTopics(min int) {
+	self.minTopics = min
+}
+
+// SetExactTopics restricts the matched logs to the ones having exactly the
+// given number of topics (e.g. to tell apart events with the same signature but
+// a different number of indexed arguments). A negative value disables the check.
+func (self *Filter) SetExactTopics(exact int) {
+	self.exactTopics, self.hasExact = exact, exact >= 0
 }
 
 func (self *Filter) SetMax(max int) {
@@ -60,8 +110,89 @@ func (self *Filter) SetSkip(skip int) {
 	self.skip = skip
 }
 
-// Run filters logs with the current parameters set
+// SetReverse switches the filter into strict newest first mode: the logs within
+// each block are returned in reverse execution order too, and the search stops
+// as soon as skip+max logs were found instead of scanning the entire range.
+func (self *Filter) SetReverse(reverse bool) {
+	self.reverse = reverse
+}
+
+// Run filters logs with the current parameters set. Blocks are visited from the
+// latest towards the earliest one, the logs within a block are returned in their
+// execution order.
+//
+// In reverse mode the logs within a block are reversed too, and if a maximum is
+// set the search terminates as soon as enough logs were collected, without
+// touching older history.
 func (self *Filter) Find() state.Logs {
+	var logs state.Logs
+	err := self.scan(nil, func(matched state.Logs) bool {
+		logs = append(logs, matched...)
+
+		// Quit if enough logs were found
+		return !self.reverse || self.max <= 0 || len(logs) < self.skip+self.max
+	})
+	if err != nil {
+		chainlogger.Warnln("err: filter get logs ", err)
+	}
+
+	skip := int(math.Min(float64(len(logs)), float64(self.skip)))
+	logs = logs[skip:]
+	if self.reverse && self.max > 0 && len(logs) > self.max {
+		logs = logs[:self.max]
+	}
+
+	return logs
+}
+
+// FindStream runs the same search as Find, but instead of accumulating all the
+// results, it sends the matching logs of each block on the returned channel as
+// soon as they are found, in the same order. Skip and max are not
+// applied; the consumer may abort the search at any point by closing quit.
+//
+// The logs channel is closed when the search ends. Any error that aborted the
+// search is delivered on the error channel before that.
+//
+// FindStream is only available through the Go API; the RPC layer still uses Find.
+func (self *Filter) FindStream(quit <-chan struct{}) (<-chan state.Logs, <-chan error) {
+	var (
+		logsc = make(chan state.Logs)
+		errc  = make(chan error, 1)
+	)
+	go func() {
+		defer close(logsc)
+		defer close(errc)
+
+		err := self.scan(quit, func(matched state.Logs) bool {
+			// Never deliver after cancellation, even if the consumer is still receiving
+			select {
+			case <-quit:
+				return false
+			default:
+			}
+			select {
+			case logsc <- matched:
+				return true
+			case <-quit:
+				return false
+			}
+		})
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return logsc, errc
+}
+
+// scan walks the blocks of the filter's range from the latest towards the
+// earliest one, invoking fn with the matching logs of every block containing
+// any (reversed in reverse mode). The walk is aborted if fn returns false or
+// quit is closed, the latter being checked before every block.
+func (self *Filter) scan(quit <-chan struct{}, fn func(state.Logs) bool) error {
+	// Don't walk the chain at all if no log can ever match
+	if self.unsatisfiable() {
+		return nil
+	}
 	earliestBlock := self.eth.ChainManager().CurrentBlock()
 	var earliestBlockNo uint64 = uint64(self.earliest)
 	if self.earliest == -1 {
@@ -73,17 +204,22 @@ func (self *Filter) Find() state.Logs {
 	}
 
 	var (
-		logs  state.Logs
 		block = self.eth.ChainManager().GetBlockByNumber(latestBlockNo)
-		quit  bool
+		done  bool
 	)
-	for i := 0; !quit && block != nil; i++ {
+	for !done && block != nil {
+		// Abort if the search was cancelled
+		select {
+		case <-quit:
+			return nil
+		default:
+		}
+		if self.scanHook != nil {
+			self.scanHook(block)
+		}
 		// Quit on latest
-		switch {
-		case block.NumberU64() == earliestBlockNo, block.NumberU64() == 0:
-			quit = true
-		case self.max <= len(logs):
-			break
+		if block.NumberU64() == earliestBlockNo || block.NumberU64() == 0 {
+			done = true
 		}
 
 		// Use bloom filtering to see if this block is interesting given the
@@ -92,20 +228,28 @@ func (self *Filter) Find() state.Logs {
 			// Get the logs of the block
 			unfiltered, err := self.eth.BlockProcessor().GetLogs(block)
 			if err != nil {
-				chainlogger.Warnln("err: filter get logs ", err)
-
-				break
+				return err
+			}
+			logs := self.FilterLogs(unfiltered)
+			if self.reverse {
+				for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+					logs[i], logs[j] = logs[j], logs[i]
+				}
+			}
+			if len(logs) > 0 && !fn(logs) {
+				return nil
 			}
-
-			logs = append(logs, self.FilterLogs(unfiltered)...)
 		}
 
 		block = self.eth.ChainManager().GetBlock(block.ParentHash())
 	}
+	return nil
+}
 
-	skip := int(math.Min(float64(len(logs)), float64(self.skip)))
-
-	return logs[skip:]
+// unsatisfiable reports whether the topic count constraints contradict each
+// other or the topic positions being filtered on, so no log can ever match.
+func (self *Filter) unsatisfiable() bool {
+	return self.hasExact && (len(self.topics) > self.exactTopics || self.minTopics > self.exactTopics)
 }
 
 func includes(addresses []common.Address, a common.Address) bool {
@@ -127,6 +271,15 @@ Logs:
 		if len(self.address) > 0 && !includes(self.address, log.Address) {
 			continue
 		}
+		if _, excluded := self.excluded[log.Address]; excluded {
+			continue
+		}
+		if len(log.Topics) < self.minTopics {
+			continue
+		}
+		if self.hasExact && len(log.Topics) != self.exactTopics {
+			continue
+		}
 
 		logTopics := make([]common.Hash, len(self.topics))
 		copy(logTopics, log.Topics)
@@ -160,10 +313,16 @@ Logs:
 }
 
 func (self *Filter) bloomFilter(block *types.Block) bool {
-	if len(self.address) > 0 {
+	// The bloom bits of the filter criteria are precomputed in the setters, only
+	// the header bloom needs converting for every block
+	var (
+		bloom = block.Bloom().Big()
+		and   = new(big.Int)
+	)
+	if len(self.addressBits) > 0 {
 		var included bool
-		for _, addr := range self.address {
-			if types.BloomLookup(block.Bloom(), addr) {
+		for _, bits := range self.addressBits {
+			if and.And(bloom, bits).Cmp(bits) == 0 {
 				included = true
 				break
 			}
@@ -174,10 +333,10 @@ func (self *Filter) bloomFilter(block *types.Block) bool {
 		}
 	}
 
-	for _, sub := range self.topics {
+	for _, sub := range self.topicBits {
 		var included bool
-		for _, topic := range sub {
-			if (topic == common.Hash{}) || types.BloomLookup(block.Bloom(), topic) {
+		for _, bits := range sub {
+			if bits == nil || and.And(bloom, bits).Cmp(bits) == 0 {
 				included = true
 				break
 			}
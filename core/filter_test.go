@@ -1 +1,368 @@
 package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// logTopic is the topic of the index'th log emitted in block number of a chain
+// created by newLogChain.
+func logTopic(number uint64, index int) common.Hash {
+	return common.BigToHash(big.NewInt(int64(number)<<4 | int64(index)))
+}
+
+// newLogChain creates a test backend with a chain of len(counts) blocks on top of
+// the genesis, the i'th block containing a contract creation emitting counts[i]
+// logs (or no transaction at all if zero), each tagged with logTopic.
+func newLogChain(t *testing.T, counts ...int) *TestManager {
+	key, _ := crypto.GenerateKey()
+
+	var (
+		manager = NewTestManager()
+		parent  = manager.ChainManager().CurrentBlock()
+		nonce   uint64
+	)
+	for i, count := range counts {
+		block := newBlockFromParent(common.Address{}, parent)
+
+		var txs types.Transactions
+		if count > 0 {
+			var code []byte
+			for j := 0; j < count; j++ {
+				code = append(code, byte(vm.PUSH1), byte(logTopic(uint64(i+1), j)[31]), byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.LOG1))
+			}
+			tx := types.NewContractCreationTx(common.Big0, big.NewInt(100000), common.Big0, code)
+			tx.SetNonce(nonce)
+			tx.SignECDSA(key)
+			nonce++
+			txs = append(txs, tx)
+		}
+		block.SetTransactions(txs)
+
+		// Execute the transactions to fill in the header fields depending on them
+		var (
+			statedb  = state.New(parent.Root(), manager.db)
+			coinbase = statedb.GetOrNewStateObject(block.Coinbase())
+			gasUsed  = new(big.Int)
+			receipts types.Receipts
+		)
+		coinbase.SetGasPool(block.GasLimit())
+		for j, tx := range txs {
+			statedb.StartRecord(tx.Hash(), block.Hash(), j)
+			receipt, _, err := manager.BlockProcessor().ApplyTransaction(coinbase, statedb, block, tx, gasUsed, true)
+			if err != nil {
+				t.Fatalf("block %d: failed to apply transaction: %v", i+1, err)
+			}
+			receipts = append(receipts, receipt)
+		}
+		block.Header().GasUsed = gasUsed
+		block.SetReceipts(receipts)
+
+		AccumulateRewards(statedb, block)
+		statedb.Update()
+		block.SetRoot(statedb.Root())
+
+		if _, err := manager.ChainManager().InsertChain(types.Blocks{block}); err != nil {
+			t.Fatalf("block %d: failed to insert: %v", i+1, err)
+		}
+		parent = block
+	}
+	return manager
+}
+
+func makeTopicLogs(addr common.Address, counts ...int) state.Logs {
+	logs := make(state.Logs, len(counts))
+	for i, n := range counts {
+		topics := make([]common.Hash, n)
+		for j := range topics {
+			topics[j] = common.BytesToHash([]byte{byte(j + 1)})
+		}
+		logs[i] = state.NewLog(addr, topics, nil, 0)
+	}
+	return logs
+}
+
+func TestFilterLogsTopicCount(t *testing.T) {
+	logs := makeTopicLogs(common.HexToAddress("0x01"), 0, 1, 2, 3, 4)
+
+	tests := []struct {
+		min, exact int
+		topics     [][]common.Hash
+		want       []int
+	}{
+		{0, -1, nil, []int{0, 1, 2, 3, 4}},
+		{2, -1, nil, []int{2, 3, 4}},
+		{0, 3, nil, []int{3}},
+		{0, 0, nil, []int{0}},
+		{4, 3, nil, nil},
+		{0, 4, [][]common.Hash{{common.BytesToHash([]byte{1})}}, []int{4}},
+	}
+	for i, tt := range tests {
+		filter := NewFilter(nil)
+		filter.SetMinTopics(tt.min)
+		filter.SetExactTopics(tt.exact)
+		filter.SetTopics(tt.topics)
+
+		matched := filter.FilterLogs(logs)
+		if len(matched) != len(tt.want) {
+			t.Errorf("test %d: matched %d logs, want %d", i, len(matched), len(tt.want))
+			continue
+		}
+		for j, log := range matched {
+			if len(log.Topics) != tt.want[j] {
+				t.Errorf("test %d: log %d has %d topics, want %d", i, j, len(log.Topics), tt.want[j])
+			}
+		}
+	}
+}
+
+func TestFilterBloom(t *testing.T) {
+	var (
+		addr   = common.HexToAddress("0x01")
+		other  = common.HexToAddress("0x02")
+		topic  = common.HexToHash("0x03")
+		absent = common.HexToHash("0x04")
+	)
+	logs := state.Logs{state.NewLog(addr, []common.Hash{topic}, nil, 0)}
+	block := types.NewBlockWithHeader(&types.Header{Bloom: types.BytesToBloom(types.LogsBloom(logs).Bytes())})
+
+	tests := []struct {
+		address []common.Address
+		topics  [][]common.Hash
+		want    bool
+	}{
+		{nil, nil, true},
+		{[]common.Address{addr}, nil, true},
+		{[]common.Address{other}, nil, false},
+		{[]common.Address{other, addr}, nil, true},
+		{nil, [][]common.Hash{{topic}}, true},
+		{nil, [][]common.Hash{{absent}}, false},
+		{nil, [][]common.Hash{{absent, topic}}, true},
+		{nil, [][]common.Hash{{common.Hash{}}}, true},
+		{[]common.Address{addr}, [][]common.Hash{{topic}, {absent}}, false},
+	}
+	for i, tt := range tests {
+		filter := NewFilter(nil)
+		filter.SetAddress(tt.address)
+		filter.SetTopics(tt.topics)
+
+		if have := filter.bloomFilter(block); have != tt.want {
+			t.Errorf("test %d: bloom match mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
+
+func TestFilterLogsExcluded(t *testing.T) {
+	var (
+		addr1 = common.HexToAddress("0x01")
+		addr2 = common.HexToAddress("0x02")
+		addr3 = common.HexToAddress("0x03")
+	)
+	logs := append(append(makeTopicLogs(addr1, 1), makeTopicLogs(addr2, 1)...), makeTopicLogs(addr3, 1)...)
+
+	filter := NewFilter(nil)
+	filter.SetExcludedAddresses([]common.Address{addr2, addr3})
+
+	matched := filter.FilterLogs(logs)
+	if len(matched) != 1 || matched[0].Address != addr1 {
+		t.Fatalf("excluded addresses not filtered: have %v", matched)
+	}
+}
+
+func TestFilterFindLimit(t *testing.T) {
+	manager := newLogChain(t, 2, 2, 0, 2, 2)
+
+	type ref struct {
+		number uint64
+		index  int
+	}
+	tests := []struct {
+		skip, max int
+		want      []ref
+		last      uint64 // Last block the logs were retrieved of
+	}{
+		{0, 0, []ref{{5, 1}, {5, 0}, {4, 1}, {4, 0}, {2, 1}, {2, 0}, {1, 1}, {1, 0}}, 1},
+		{0, 1, []ref{{5, 1}}, 5},
+		{0, 3, []ref{{5, 1}, {5, 0}, {4, 1}}, 4},
+		{1, 3, []ref{{5, 0}, {4, 1}, {4, 0}}, 4},
+		{4, 1, []ref{{2, 1}}, 2},
+		{6, 0, []ref{{1, 1}, {1, 0}}, 1},
+		{7, 5, []ref{{1, 0}}, 1},
+		{9, 5, nil, 1},
+	}
+	for i, tt := range tests {
+		filter := NewFilter(manager)
+		filter.SetEarliestBlock(1)
+		filter.SetLatestBlock(-1)
+		filter.SetSkip(tt.skip)
+		filter.SetMax(tt.max)
+		filter.SetReverse(true)
+
+		logs := filter.Find()
+		if len(logs) != len(tt.want) {
+			t.Errorf("test %d: found %d logs, want %d", i, len(logs), len(tt.want))
+			continue
+		}
+		for j, log := range logs {
+			if log.Number != tt.want[j].number || log.Topics[0] != logTopic(tt.want[j].number, tt.want[j].index) {
+				t.Errorf("test %d: log %d mismatch: have #%d %x, want #%d %x", i, j, log.Number, log.Topics[0], tt.want[j].number, logTopic(tt.want[j].number, tt.want[j].index))
+			}
+		}
+		if last := manager.BlockProcessor().lastAttemptedBlock.NumberU64(); last != tt.last {
+			t.Errorf("test %d: scanned down to block #%d, want #%d", i, last, tt.last)
+		}
+	}
+}
+
+func TestFilterUnsatisfiable(t *testing.T) {
+	tests := []struct {
+		min, exact int
+		topics     [][]common.Hash
+	}{
+		{0, 1, [][]common.Hash{{}, {}}},
+		{3, 2, nil},
+	}
+	for i, tt := range tests {
+		// A nil backend makes sure the chain is never touched
+		filter := NewFilter(nil)
+		filter.SetEarliestBlock(0)
+		filter.SetLatestBlock(-1)
+		filter.SetMinTopics(tt.min)
+		filter.SetExactTopics(tt.exact)
+		filter.SetTopics(tt.topics)
+
+		if logs := filter.Find(); len(logs) != 0 {
+			t.Errorf("test %d: found %d logs, want none", i, len(logs))
+		}
+	}
+}
+
+func TestFilterZeroValueTopicCount(t *testing.T) {
+	// A literal filter (not created via NewFilter) must not restrict topic counts
+	filter := &Filter{}
+	if matched := filter.FilterLogs(makeTopicLogs(common.HexToAddress("0x01"), 0, 1, 2)); len(matched) != 3 {
+		t.Errorf("matched %d logs, want 3", len(matched))
+	}
+}
+
+func TestFilterFindDefaultOrder(t *testing.T) {
+	manager := newLogChain(t, 2, 2, 0, 2, 2)
+
+	type ref struct {
+		number uint64
+		index  int
+	}
+	tests := []struct {
+		skip, max int
+		want      []ref
+	}{
+		// Without reverse mode logs keep their execution order within blocks and
+		// the maximum (set to 100 by the RPC layer by default) is not enforced
+		{0, 0, []ref{{5, 0}, {5, 1}, {4, 0}, {4, 1}, {2, 0}, {2, 1}, {1, 0}, {1, 1}}},
+		{0, 1, []ref{{5, 0}, {5, 1}, {4, 0}, {4, 1}, {2, 0}, {2, 1}, {1, 0}, {1, 1}}},
+		{5, 1, []ref{{2, 1}, {1, 0}, {1, 1}}},
+	}
+	for i, tt := range tests {
+		filter := NewFilter(manager)
+		filter.SetEarliestBlock(1)
+		filter.SetLatestBlock(-1)
+		filter.SetSkip(tt.skip)
+		filter.SetMax(tt.max)
+
+		logs := filter.Find()
+		if len(logs) != len(tt.want) {
+			t.Errorf("test %d: found %d logs, want %d", i, len(logs), len(tt.want))
+			continue
+		}
+		for j, log := range logs {
+			if log.Number != tt.want[j].number || log.Topics[0] != logTopic(tt.want[j].number, tt.want[j].index) {
+				t.Errorf("test %d: log %d mismatch: have #%d %x, want #%d %x", i, j, log.Number, log.Topics[0], tt.want[j].number, logTopic(tt.want[j].number, tt.want[j].index))
+			}
+		}
+		if last := manager.BlockProcessor().lastAttemptedBlock.NumberU64(); last != 1 {
+			t.Errorf("test %d: scanned down to block #%d, want #1", i, last)
+		}
+	}
+}
+
+func TestFilterFindStream(t *testing.T) {
+	manager := newLogChain(t, 2, 0, 1, 0, 0, 2)
+
+	newFilter := func(latest int64) *Filter {
+		filter := NewFilter(manager)
+		filter.SetEarliestBlock(1)
+		filter.SetLatestBlock(latest)
+		return filter
+	}
+	// Stream the entire range and check that batches arrive per block, newest first
+	logsc, errc := newFilter(-1).FindStream(make(chan struct{}))
+
+	want := [][]int{6: {0, 1}, 3: {0}, 1: {0, 1}}
+	for _, number := range []uint64{6, 3, 1} {
+		logs, ok := <-logsc
+		if !ok {
+			t.Fatalf("stream closed before block #%d", number)
+		}
+		if len(logs) != len(want[number]) {
+			t.Fatalf("block #%d: have %d logs, want %d", number, len(logs), len(want[number]))
+		}
+		for i, log := range logs {
+			if log.Number != number || log.Topics[0] != logTopic(number, want[number][i]) {
+				t.Errorf("block #%d: log %d mismatch: have #%d %x", number, i, log.Number, log.Topics[0])
+			}
+		}
+	}
+	if logs, ok := <-logsc; ok {
+		t.Fatalf("unexpected batch after the last block: %v", logs)
+	}
+	if err, ok := <-errc; ok {
+		t.Fatalf("error channel not closed: %v", err)
+	}
+	// Cancel after the first batch and check that nothing else is delivered
+	quit := make(chan struct{})
+	logsc, errc = newFilter(-1).FindStream(quit)
+	if logs := <-logsc; len(logs) == 0 || logs[0].Number != 6 {
+		t.Fatalf("first batch mismatch: %v", logs)
+	}
+	close(quit)
+	if err, ok := <-errc; ok {
+		t.Fatalf("error channel not closed: %v", err)
+	}
+	if logs, ok := <-logsc; ok {
+		t.Fatalf("unexpected batch after cancellation: %v", logs)
+	}
+	// Cancel a walk while it is in the blocks above #3, all rejected by the bloom
+	// filter, and check that it stops there, never retrieving the logs of #3
+	var visited []uint64
+
+	quit = make(chan struct{})
+	filter := newFilter(-1)
+	filter.SetTopics([][]common.Hash{{logTopic(3, 0)}})
+	filter.scanHook = func(block *types.Block) {
+		visited = append(visited, block.NumberU64())
+		if block.NumberU64() == 5 {
+			close(quit)
+		}
+	}
+	manager.BlockProcessor().lastAttemptedBlock = nil
+
+	logsc, errc = filter.FindStream(quit)
+	if logs, ok := <-logsc; ok {
+		t.Fatalf("unexpected batch after cancellation: %v", logs)
+	}
+	if err, ok := <-errc; ok {
+		t.Fatalf("error channel not closed: %v", err)
+	}
+	if len(visited) != 2 || visited[0] != 6 || visited[1] != 5 {
+		t.Errorf("visited blocks mismatch: have %v, want [6 5]", visited)
+	}
+	if block := manager.BlockProcessor().lastAttemptedBlock; block != nil {
+		t.Fatalf("cancelled search retrieved the logs of block #%d", block.NumberU64())
+	}
+}
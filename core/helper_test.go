@@ -1,7 +1,6 @@
 package core
 
 import (
-	"container/list"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/core/types"
@@ -9,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/p2p"
 )
 
 // Implement our EthTest Manager
@@ -16,10 +16,11 @@ type TestManager struct {
 	// stateManager *StateManager
 	eventMux *event.TypeMux
 
-	db         common.Database
-	txPool     *TxPool
-	blockChain *ChainManager
-	Blocks     []*types.Block
+	db             common.Database
+	txPool         *TxPool
+	blockChain     *ChainManager
+	blockProcessor *BlockProcessor
+	Blocks         []*types.Block
 }
 
 func (s *TestManager) IsListening() bool {
@@ -34,8 +35,8 @@ func (s *TestManager) PeerCount() int {
 	return 0
 }
 
-func (s *TestManager) Peers() *list.List {
-	return list.New()
+func (s *TestManager) Peers() []*p2p.Peer {
+	return nil
 }
 
 func (s *TestManager) ChainManager() *ChainManager {
@@ -46,6 +47,10 @@ func (tm *TestManager) TxPool() *TxPool {
 	return tm.txPool
 }
 
+func (tm *TestManager) BlockProcessor() *BlockProcessor {
+	return tm.blockProcessor
+}
+
 // func (tm *TestManager) StateManager() *StateManager {
 // 	return tm.stateManager
 // }
@@ -62,6 +67,14 @@ func (tm *TestManager) Db() common.Database {
 	return tm.db
 }
 
+func (tm *TestManager) BlockDb() common.Database {
+	return tm.db
+}
+
+func (tm *TestManager) StateDb() common.Database {
+	return tm.db
+}
+
 func NewTestManager() *TestManager {
 	db, err := ethdb.NewMemDatabase()
 	if err != nil {
@@ -72,12 +85,10 @@ func NewTestManager() *TestManager {
 	testManager := &TestManager{}
 	testManager.eventMux = new(event.TypeMux)
 	testManager.db = db
-	// testManager.txPool = NewTxPool(testManager)
-	// testManager.blockChain = NewChainManager(testManager)
-	// testManager.stateManager = NewStateManager(testManager)
-
-	// Start the tx pool
-	testManager.txPool.Start()
+	testManager.blockProcessor = newBlockProcessor(db, nil, testManager.eventMux)
+	testManager.blockChain = testManager.blockProcessor.bc
+	testManager.blockChain.SetProcessor(testManager.blockProcessor)
+	testManager.txPool = testManager.blockProcessor.txpool
 
 	return testManager
 }
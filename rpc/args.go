@@ -630,22 +630,26 @@ func (args *Sha3Args) UnmarshalJSON(b []byte) (err error) {
 }
 
 type BlockFilterArgs struct {
-	Earliest int64
-	Latest   int64
-	Address  []string
-	Topics   [][]string
-	Skip     int
-	Max      int
+	Earliest    int64
+	Latest      int64
+	Address     []string
+	Topics      [][]string
+	Skip        int
+	Max         int
+	MinTopics   int
+	ExactTopics int
 }
 
 func (args *BlockFilterArgs) UnmarshalJSON(b []byte) (err error) {
 	var obj []struct {
-		FromBlock interface{} `json:"fromBlock"`
-		ToBlock   interface{} `json:"toBlock"`
-		Limit     interface{} `json:"limit"`
-		Offset    interface{} `json:"offset"`
-		Address   interface{} `json:"address"`
-		Topics    interface{} `json:"topics"`
+		FromBlock   interface{} `json:"fromBlock"`
+		ToBlock     interface{} `json:"toBlock"`
+		Limit       interface{} `json:"limit"`
+		Offset      interface{} `json:"offset"`
+		Address     interface{} `json:"address"`
+		Topics      interface{} `json:"topics"`
+		MinTopics   interface{} `json:"minTopics"`
+		ExactTopics interface{} `json:"exactTopics"`
 	}
 
 	if err = json.Unmarshal(b, &obj); err != nil {
@@ -710,6 +714,22 @@ func (args *BlockFilterArgs) UnmarshalJSON(b []byte) (err error) {
 	}
 	args.Skip = int(numBig.Int64())
 
+	if obj[0].MinTopics != nil {
+		if numBig, err = numString(obj[0].MinTopics); err != nil {
+			return err
+		}
+		args.MinTopics = int(numBig.Int64())
+	}
+
+	// if blank then any number of topics
+	args.ExactTopics = -1
+	if obj[0].ExactTopics != nil {
+		if numBig, err = numString(obj[0].ExactTopics); err != nil {
+			return err
+		}
+		args.ExactTopics = int(numBig.Int64())
+	}
+
 	if obj[0].Address != nil {
 		marg, ok := obj[0].Address.([]interface{})
 		if ok {
@@ -1265,6 +1265,8 @@ func TestBlockFilterArgs(t *testing.T) {
   "toBlock": "0x2",
   "limit": "0x3",
   "offset": "0x0",
+  "minTopics": "0x2",
+  "exactTopics": 3,
   "address": "0xd5677cf67b5aa051bb40496e68ad359eb97cfbf8",
   "topics":
   [
@@ -1278,6 +1280,8 @@ func TestBlockFilterArgs(t *testing.T) {
 	expected.Latest = 2
 	expected.Max = 3
 	expected.Skip = 0
+	expected.MinTopics = 2
+	expected.ExactTopics = 3
 	expected.Address = []string{"0xd5677cf67b5aa051bb40496e68ad359eb97cfbf8"}
 	expected.Topics = [][]string{
 		[]string{"0xAA", "0xBB"},
@@ -1305,6 +1309,14 @@ func TestBlockFilterArgs(t *testing.T) {
 		t.Errorf("Skip shoud be %#v but is %#v", expected.Skip, args.Skip)
 	}
 
+	if expected.MinTopics != args.MinTopics {
+		t.Errorf("MinTopics shoud be %#v but is %#v", expected.MinTopics, args.MinTopics)
+	}
+
+	if expected.ExactTopics != args.ExactTopics {
+		t.Errorf("ExactTopics shoud be %#v but is %#v", expected.ExactTopics, args.ExactTopics)
+	}
+
 	if expected.Address[0] != args.Address[0] {
 		t.Errorf("Address shoud be %#v but is %#v", expected.Address, args.Address)
 	}
@@ -1334,6 +1346,8 @@ func TestBlockFilterArgsDefaults(t *testing.T) {
 	expected.Latest = -1
 	expected.Max = 100
 	expected.Skip = 0
+	expected.MinTopics = 0
+	expected.ExactTopics = -1
 	expected.Address = []string{"0xd5677cf67b5aa051bb40496e68ad359eb97cfbf8"}
 	expected.Topics = [][]string{[]string{"0xAA"}, []string{"0xBB"}}
 
@@ -1358,6 +1372,14 @@ func TestBlockFilterArgsDefaults(t *testing.T) {
 		t.Errorf("Skip shoud be %#v but is %#v", expected.Skip, args.Skip)
 	}
 
+	if expected.MinTopics != args.MinTopics {
+		t.Errorf("MinTopics shoud be %#v but is %#v", expected.MinTopics, args.MinTopics)
+	}
+
+	if expected.ExactTopics != args.ExactTopics {
+		t.Errorf("ExactTopics shoud be %#v but is %#v", expected.ExactTopics, args.ExactTopics)
+	}
+
 	if expected.Address[0] != args.Address[0] {
 		t.Errorf("Address shoud be %#v but is %#v", expected.Address, args.Address)
 	}
@@ -1450,6 +1472,26 @@ func TestBlockFilterArgsLimitInvalid(t *testing.T) {
 	}
 }
 
+func TestBlockFilterArgsMinTopicsInvalid(t *testing.T) {
+	input := `[{"minTopics": false}]`
+
+	args := new(BlockFilterArgs)
+	str := ExpectInvalidTypeError(json.Unmarshal([]byte(input), &args))
+	if len(str) > 0 {
+		t.Error(str)
+	}
+}
+
+func TestBlockFilterArgsExactTopicsInvalid(t *testing.T) {
+	input := `[{"exactTopics": true}]`
+
+	args := new(BlockFilterArgs)
+	str := ExpectInvalidTypeError(json.Unmarshal([]byte(input), &args))
+	if len(str) > 0 {
+		t.Error(str)
+	}
+}
+
 func TestBlockFilterArgsOffsetInvalid(t *testing.T) {
 	input := `[{"offset": true}]`
 
@@ -360,13 +360,15 @@ func (self *XEth) SecretToAddress(key string) string {
 	return common.ToHex(pair.Address())
 }
 
-func (self *XEth) RegisterFilter(earliest, latest int64, skip, max int, address []string, topics [][]string) int {
+func (self *XEth) RegisterFilter(earliest, latest int64, skip, max, minTopics, exactTopics int, address []string, topics [][]string) int {
 	var id int
 	filter := core.NewFilter(self.backend)
 	filter.SetEarliestBlock(earliest)
 	filter.SetLatestBlock(latest)
 	filter.SetSkip(skip)
 	filter.SetMax(max)
+	filter.SetMinTopics(minTopics)
+	filter.SetExactTopics(exactTopics)
 	filter.SetAddress(cAddress(address))
 	filter.SetTopics(cTopics(topics))
 	filter.LogsCallback = func(logs state.Logs) {
@@ -444,12 +446,14 @@ func (self *XEth) Logs(id int) state.Logs {
 	return nil
 }
 
-func (self *XEth) AllLogs(earliest, latest int64, skip, max int, address []string, topics [][]string) state.Logs {
+func (self *XEth) AllLogs(earliest, latest int64, skip, max, minTopics, exactTopics int, address []string, topics [][]string) state.Logs {
 	filter := core.NewFilter(self.backend)
 	filter.SetEarliestBlock(earliest)
 	filter.SetLatestBlock(latest)
 	filter.SetSkip(skip)
 	filter.SetMax(max)
+	filter.SetMinTopics(minTopics)
+	filter.SetExactTopics(exactTopics)
 	filter.SetAddress(cAddress(address))
 	filter.SetTopics(cTopics(topics))
 